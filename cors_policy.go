@@ -0,0 +1,108 @@
+package tigertonic
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response headers used by CORSPolicy that are not already defined alongside
+// CORSAllowOrigin and CORSAllowHeaders.
+const (
+	CORSAllowCredentials = "Access-Control-Allow-Credentials"
+	CORSExposeHeaders    = "Access-Control-Expose-Headers"
+	CORSMaxAge           = "Access-Control-Max-Age"
+)
+
+// CORSPolicy describes how a subtree of a TrieServeMux responds to
+// cross-origin requests.  Attach one to a pattern with
+// TrieServeMux.HandleCORS; the most specific policy along a matched path is
+// used both to synthesize OPTIONS preflight responses and to annotate actual
+// responses with Access-Control-* headers, replacing the ad-hoc inspection
+// of a single *CORSHandler that methodNotAllowedHandler used to perform.
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	MaxAge           int
+	AllowCredentials bool
+}
+
+// HandleCORS attaches policy to the subtree of mux rooted at pattern.  Every
+// route registered under pattern, whether registered before or after this
+// call, is governed by policy, unless a more specific pattern further down
+// the tree has its own policy.
+func (mux *TrieServeMux) HandleCORS(pattern string, policy *CORSPolicy) {
+	mux.addCORSPolicy(splitPattern(pattern), policy)
+}
+
+// addCORSPolicy recursively descends to the node for paths, creating
+// intermediate nodes as add does, and attaches policy there.
+func (mux *TrieServeMux) addCORSPolicy(paths []string, policy *CORSPolicy) {
+	if 0 == len(paths) {
+		mux.corsPolicy = policy
+		return
+	}
+	if _, ok := mux.paths[paths[0]]; !ok {
+		mux.paths[paths[0]] = NewTrieServeMux()
+	}
+	mux.paths[paths[0]].addCORSPolicy(paths[1:], policy)
+}
+
+// allowedOrigin returns the value that should be sent as
+// Access-Control-Allow-Origin in reply to a request from origin, or "" if
+// origin is not allowed by policy.
+func (policy *CORSPolicy) allowedOrigin(origin string) string {
+	for _, allowed := range policy.AllowedOrigins {
+		if "*" == allowed || allowed == origin {
+			return allowed
+		}
+	}
+	return ""
+}
+
+// setHeaders writes the Access-Control-* headers that apply to both
+// preflight and actual requests from origin.
+func (policy *CORSPolicy) setHeaders(w http.ResponseWriter, origin string) {
+	allowed := policy.allowedOrigin(origin)
+	if "" == allowed {
+		return
+	}
+	w.Header().Set(CORSAllowOrigin, allowed)
+	if policy.AllowCredentials {
+		w.Header().Set(CORSAllowCredentials, "true")
+	}
+	if 0 != len(policy.ExposedHeaders) {
+		w.Header().Set(CORSExposeHeaders, strings.Join(policy.ExposedHeaders, ", "))
+	}
+}
+
+// wrap returns handler augmented with policy's Access-Control-* headers on
+// every response, and with automatic preflight headers on OPTIONS requests,
+// whether or not handler itself writes a preflight response (which happens
+// when no OPTIONS handler was registered for the matched path and handler is
+// the synthetic methodNotAllowedHandler).
+func (policy *CORSPolicy) wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get(CORSRequestOrigin); "" != origin {
+			policy.setHeaders(w, origin)
+		}
+		if "OPTIONS" == r.Method && "" != r.Header.Get(CORSRequestMethod) {
+			if 0 != len(policy.AllowedMethods) {
+				w.Header().Set(CORSAllowMethods, strings.Join(policy.AllowedMethods, ", "))
+			}
+			if requestHeaders := r.Header.Get(CORSRequestHeaders); "" != requestHeaders {
+				if 0 != len(policy.AllowedHeaders) {
+					w.Header().Set(CORSAllowHeaders, strings.Join(policy.AllowedHeaders, ", "))
+				} else {
+					w.Header().Set(CORSAllowHeaders, requestHeaders)
+				}
+			}
+			if 0 < policy.MaxAge {
+				w.Header().Set(CORSMaxAge, strconv.Itoa(policy.MaxAge))
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
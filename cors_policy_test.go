@@ -0,0 +1,71 @@
+package tigertonic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSPolicySynthesizesPreflight(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/foo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.HandleCORS("/foo", &CORSPolicy{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET", "OPTIONS"},
+		MaxAge:         600,
+	})
+
+	r := httptest.NewRequest("OPTIONS", "/foo", nil)
+	r.Header.Set(CORSRequestOrigin, "https://example.com")
+	r.Header.Set(CORSRequestMethod, "GET")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	if got, want := w.Header().Get(CORSAllowOrigin), "https://example.com"; got != want {
+		t.Fatalf("expected %s %q, got %q", CORSAllowOrigin, want, got)
+	}
+	if got, want := w.Header().Get(CORSAllowMethods), "GET, OPTIONS"; got != want {
+		t.Fatalf("expected %s %q, got %q", CORSAllowMethods, want, got)
+	}
+	if got, want := w.Header().Get(CORSMaxAge), "600"; got != want {
+		t.Fatalf("expected %s %q, got %q", CORSMaxAge, want, got)
+	}
+}
+
+func TestCORSPolicyRejectsDisallowedOrigin(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/foo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.HandleCORS("/foo", &CORSPolicy{AllowedOrigins: []string{"https://example.com"}})
+
+	r := httptest.NewRequest("GET", "/foo", nil)
+	r.Header.Set(CORSRequestOrigin, "https://evil.example")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	if "" != w.Header().Get(CORSAllowOrigin) {
+		t.Fatalf("expected no %s for a disallowed origin, got %q", CORSAllowOrigin, w.Header().Get(CORSAllowOrigin))
+	}
+}
+
+func TestCORSPolicyAnnotatesActualResponse(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/foo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	mux.HandleCORS("/foo", &CORSPolicy{
+		AllowedOrigins: []string{"https://example.com"},
+		ExposedHeaders: []string{"X-Custom"},
+	})
+
+	r := httptest.NewRequest("GET", "/foo", nil)
+	r.Header.Set(CORSRequestOrigin, "https://example.com")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	if got, want := w.Header().Get(CORSAllowOrigin), "https://example.com"; got != want {
+		t.Fatalf("expected %s %q on an actual response, got %q", CORSAllowOrigin, want, got)
+	}
+	if got, want := w.Header().Get(CORSExposeHeaders), "X-Custom"; got != want {
+		t.Fatalf("expected %s %q, got %q", CORSExposeHeaders, want, got)
+	}
+}
@@ -0,0 +1,32 @@
+package tigertonic
+
+import "net/http"
+
+// headHandler dispatches a HEAD request to a GET handler with the response
+// body discarded, per TrieServeMux.AutoHead.
+type headHandler struct {
+	get http.Handler
+}
+
+func (h headHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.get.ServeHTTP(headResponseWriter{w}, r)
+}
+
+// headResponseWriter wraps an http.ResponseWriter so that Write is a no-op.
+// Headers, including Content-Length, and the status code set by the wrapped
+// GET handler are passed through unchanged, as required for a HEAD response.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Flush lets a streaming GET handler keep flushing without error; the bytes
+// are still discarded by Write above.
+func (w headResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
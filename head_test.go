@@ -0,0 +1,70 @@
+package tigertonic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestAutoHeadDispatchesToGetHandler(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/foo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := []byte("hello, world")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("HEAD", "/foo", nil))
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if "" != w.Body.String() {
+		t.Fatalf("expected an empty body, got %q", w.Body.String())
+	}
+	if got, want := w.Header().Get("Content-Length"), "12"; got != want {
+		t.Fatalf("expected Content-Length %q, got %q", want, got)
+	}
+}
+
+func TestAutoHeadDisabledFallsBackTo405(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.AutoHead = false
+	mux.Handle("GET", "/foo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello, world"))
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("HEAD", "/foo", nil))
+
+	if http.StatusMethodNotAllowed != w.Code {
+		t.Fatalf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestAutoHeadStreamingHandlerFlush(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/stream", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("expected the wrapped ResponseWriter to implement http.Flusher")
+		}
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("chunk"))
+			flusher.Flush()
+		}
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("HEAD", "/stream", nil))
+
+	if "" != w.Body.String() {
+		t.Fatalf("expected an empty body, got %q", w.Body.String())
+	}
+	if !w.Flushed {
+		t.Fatal("expected the streaming handler's Flush calls to reach the underlying ResponseWriter")
+	}
+}
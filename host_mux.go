@@ -0,0 +1,118 @@
+package tigertonic
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// hostEntry is a TrieServeMux registered for a host pattern, along with
+// whether the pattern additionally requires the request to have arrived
+// over TLS.
+type hostEntry struct {
+	mux        *TrieServeMux
+	requireTLS bool
+}
+
+// hostWildcard is a host pattern with a single "{name}"-style wildcard
+// label, matched against the literal suffix that follows it.
+type hostWildcard struct {
+	name  string
+	entry hostEntry
+	// suffix is everything in the pattern after the wildcard label, for
+	// example ".example.com" in "{sub}.example.com".
+	suffix string
+}
+
+// HostMux is an http.Handler that dispatches to a different TrieServeMux
+// based on the Host of the incoming request, mirroring gorilla/mux's Host
+// and Schemes matchers.  A host pattern is either an exact host, such as
+// "api.example.com", or a single wildcard label followed by a literal
+// suffix, such as "{sub}.example.com".  A matched wildcard is written into
+// the request's query parameters the same way TrieServeMux writes path
+// wildcards, so downstream handlers retrieve it with r.URL.Query().Get("sub").
+type HostMux struct {
+	hosts     map[string]hostEntry
+	wildcards []hostWildcard
+}
+
+// NewHostMux makes a new HostMux.
+func NewHostMux() *HostMux {
+	return &HostMux{hosts: make(map[string]hostEntry)}
+}
+
+// Handle registers mux to serve requests whose Host matches pattern,
+// regardless of scheme.
+func (hm *HostMux) Handle(pattern string, mux *TrieServeMux) {
+	hm.add(pattern, hostEntry{mux: mux})
+}
+
+// HandleTLS registers mux to serve requests whose Host matches pattern and
+// which arrived over TLS (r.TLS != nil).  Requests that match pattern but
+// did not arrive over TLS fall through as if pattern were not registered.
+func (hm *HostMux) HandleTLS(pattern string, mux *TrieServeMux) {
+	hm.add(pattern, hostEntry{mux: mux, requireTLS: true})
+}
+
+func (hm *HostMux) add(pattern string, entry hostEntry) {
+	if strings.HasPrefix(pattern, "{") {
+		if i := strings.Index(pattern, "}"); i >= 0 {
+			hm.wildcards = append(hm.wildcards, hostWildcard{
+				name:   pattern[1:i],
+				suffix: pattern[i+1:],
+				entry:  entry,
+			})
+			return
+		}
+	}
+	hm.hosts[pattern] = entry
+}
+
+// ServeHTTP dispatches r to the TrieServeMux registered for r.Host,
+// responding 404 if no host pattern matches.
+func (hm *HostMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := stripPort(r.Host)
+	if entry, ok := hm.hosts[host]; ok && entry.matches(r) {
+		entry.mux.ServeHTTP(w, r)
+		return
+	}
+	for _, wc := range hm.wildcards {
+		sub := strings.TrimSuffix(host, wc.suffix)
+		if sub == host || "" == sub || !wc.entry.matches(r) {
+			continue
+		}
+		params := make(url.Values)
+		params.Set("{"+wc.name+"}", sub)
+		params.Set(wc.name, sub)
+		r.URL.RawQuery = r.URL.RawQuery + "&" + params.Encode()
+		wc.entry.mux.ServeHTTP(w, r)
+		return
+	}
+	NotFoundHandler().ServeHTTP(w, r)
+}
+
+// matches reports whether entry's scheme requirement, if any, is satisfied
+// by r.
+func (entry hostEntry) matches(r *http.Request) bool {
+	return !entry.requireTLS || nil != r.TLS
+}
+
+// stripPort removes a trailing ":port" from host, the way net.SplitHostPort
+// would, but leaves a bracketed IPv6 literal such as "[::1]" alone when it
+// has no port, instead of truncating it at the last colon inside the
+// brackets.
+func stripPort(host string) string {
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i >= 0 {
+			if strings.HasPrefix(host[i+1:], ":") {
+				return host[:i+1]
+			}
+			return host
+		}
+		return host
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		return host[:i]
+	}
+	return host
+}
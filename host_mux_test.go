@@ -0,0 +1,107 @@
+package tigertonic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostMuxExactMatch(t *testing.T) {
+	inner := NewTrieServeMux()
+	inner.Handle("GET", "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "api")
+	}))
+	hm := NewHostMux()
+	hm.Handle("api.example.com", inner)
+
+	r := httptest.NewRequest("GET", "http://api.example.com/", nil)
+	r.Host = "api.example.com:8080"
+	w := httptest.NewRecorder()
+	hm.ServeHTTP(w, r)
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if "api" != w.Header().Get("X-Matched") {
+		t.Fatalf("expected the registered host's mux to handle the request, got %q", w.Header().Get("X-Matched"))
+	}
+}
+
+func TestHostMuxWildcardSubdomainCapture(t *testing.T) {
+	inner := NewTrieServeMux()
+	inner.Handle("GET", "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Sub", r.URL.Query().Get("sub"))
+	}))
+	hm := NewHostMux()
+	hm.Handle("{sub}.example.com", inner)
+
+	r := httptest.NewRequest("GET", "http://acme.example.com/", nil)
+	r.Host = "acme.example.com"
+	w := httptest.NewRecorder()
+	hm.ServeHTTP(w, r)
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if "acme" != w.Header().Get("X-Sub") {
+		t.Fatalf("expected sub=acme, got %q", w.Header().Get("X-Sub"))
+	}
+}
+
+func TestHostMuxHandleTLSRequiresTLS(t *testing.T) {
+	inner := NewTrieServeMux()
+	inner.Handle("GET", "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	hm := NewHostMux()
+	hm.HandleTLS("secure.example.com", inner)
+
+	r := httptest.NewRequest("GET", "http://secure.example.com/", nil)
+	r.Host = "secure.example.com"
+	w := httptest.NewRecorder()
+	hm.ServeHTTP(w, r)
+
+	if http.StatusNotFound != w.Code {
+		t.Fatalf("expected a non-TLS request to a TLS-only host pattern to 404, got %d", w.Code)
+	}
+}
+
+func TestHostMuxBracketedIPv6HostWithoutPort(t *testing.T) {
+	inner := NewTrieServeMux()
+	inner.Handle("GET", "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "v6")
+	}))
+	hm := NewHostMux()
+	hm.Handle("[::1]", inner)
+
+	r := httptest.NewRequest("GET", "http://[::1]/", nil)
+	r.Host = "[::1]"
+	w := httptest.NewRecorder()
+	hm.ServeHTTP(w, r)
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if "v6" != w.Header().Get("X-Matched") {
+		t.Fatalf("expected the [::1] host pattern to match, got %q", w.Header().Get("X-Matched"))
+	}
+}
+
+func TestHostMuxBracketedIPv6HostWithPort(t *testing.T) {
+	inner := NewTrieServeMux()
+	inner.Handle("GET", "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "v6")
+	}))
+	hm := NewHostMux()
+	hm.Handle("[::1]", inner)
+
+	r := httptest.NewRequest("GET", "http://[::1]:8080/", nil)
+	r.Host = "[::1]:8080"
+	w := httptest.NewRecorder()
+	hm.ServeHTTP(w, r)
+
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if "v6" != w.Header().Get("X-Matched") {
+		t.Fatalf("expected the [::1] host pattern to match with a port present, got %q", w.Header().Get("X-Matched"))
+	}
+}
@@ -0,0 +1,60 @@
+package tigertonic
+
+import (
+	"net/http"
+	"strings"
+)
+
+// splitPattern splits a URL pattern into its path components, discarding the
+// leading empty component produced by the leading slash.
+func splitPattern(pattern string) []string {
+	return strings.Split(pattern, "/")[1:]
+}
+
+// Middleware wraps an http.Handler with additional behavior, such as request
+// logging, authentication, or tracing, and returns the wrapped http.Handler.
+type Middleware func(http.Handler) http.Handler
+
+// Use registers middlewares to be applied, outermost first, to every request
+// that is routed to this node or any of its descendants, including requests
+// that fall through to the 404 and 405 handlers.  Middlewares registered on
+// the root TrieServeMux therefore apply to every request served by the mux.
+func (mux *TrieServeMux) Use(mw ...Middleware) {
+	mux.middleware = append(mux.middleware, mw...)
+}
+
+// UseFor registers middlewares that apply only to the given HTTP method and
+// URL pattern, closest to the matched handler.  Handle or HandleFunc must
+// have already registered a handler for method and pattern.
+func (mux *TrieServeMux) UseFor(method, pattern string, mw ...Middleware) {
+	mux.addMiddleware(method, splitPattern(pattern), mw)
+}
+
+// addMiddleware recursively descends the trie to the node for paths and
+// registers mw to apply only to method at that node.  A param segment in
+// paths (e.g. "{id:int}") is looked up by the same raw text add stored it
+// under, so UseFor's pattern must match the originally registered pattern.
+func (mux *TrieServeMux) addMiddleware(method string, paths []string, mw []Middleware) {
+	if 0 == len(paths) {
+		mux.methodMiddleware[method] = append(mux.methodMiddleware[method], mw...)
+		return
+	}
+	if node, ok := mux.paths[paths[0]]; ok {
+		node.addMiddleware(method, paths[1:], mw)
+	}
+}
+
+// concatMiddleware concatenates a and b without risking aliasing either
+// slice's backing array, which a bare append(a, b...) could overwrite.
+func concatMiddleware(a, b []Middleware) []Middleware {
+	if 0 == len(a) {
+		return b
+	}
+	if 0 == len(b) {
+		return a
+	}
+	mw := make([]Middleware, 0, len(a)+len(b))
+	mw = append(mw, a...)
+	mw = append(mw, b...)
+	return mw
+}
@@ -0,0 +1,57 @@
+package tigertonic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func recordingMiddleware(log *[]string, name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*log = append(*log, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddlewareOrderingAcrossNamespaces(t *testing.T) {
+	var log []string
+
+	mux := NewTrieServeMux()
+	mux.Use(recordingMiddleware(&log, "root"))
+	mux.HandleNamespace("/api", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.Handle("GET", "/api/users", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log = append(log, "handler")
+	}))
+	mux.UseFor("GET", "/api/users", recordingMiddleware(&log, "leaf"))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/users", nil))
+
+	want := []string{"root", "leaf", "handler"}
+	if len(want) != len(log) {
+		t.Fatalf("expected %v, got %v", want, log)
+	}
+	for i := range want {
+		if want[i] != log[i] {
+			t.Fatalf("expected %v, got %v", want, log)
+		}
+	}
+}
+
+func TestUseForDoesNotApplyToOtherMethods(t *testing.T) {
+	var log []string
+
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.Handle("POST", "/widgets", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.UseFor("GET", "/widgets", recordingMiddleware(&log, "get-only"))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/widgets", nil))
+
+	if 0 != len(log) {
+		t.Fatalf("expected UseFor(\"GET\", ...) not to run on a POST request, got %v", log)
+	}
+}
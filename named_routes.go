@@ -0,0 +1,52 @@
+package tigertonic
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HandleNamed registers handler the same way Handle does, and additionally
+// remembers pattern under name so it can later be substituted back into a
+// URL with TrieServeMux.URL.
+func (mux *TrieServeMux) HandleNamed(name, method, pattern string, handler http.Handler) {
+	mux.Handle(method, pattern, handler)
+	mux.names[name] = pattern
+}
+
+// URL builds the URL for the route registered under name with HandleNamed,
+// substituting each "{foo}" or "{foo:constraint}" segment of its pattern
+// with the value supplied for foo in pairs, which must be an even-length
+// list of alternating keys and values.  It returns an error if name is
+// unknown, a pattern parameter has no corresponding pair, or a supplied
+// value does not match its constraint.
+func (mux *TrieServeMux) URL(name string, pairs ...string) (*url.URL, error) {
+	pattern, ok := mux.names[name]
+	if !ok {
+		return nil, fmt.Errorf("tigertonic: no route named %q", name)
+	}
+	if 0 != len(pairs)%2 {
+		return nil, fmt.Errorf("tigertonic: URL called with an odd number of key/value pairs for route %q", name)
+	}
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		paramName, constraint := parseConstraint(segment)
+		value, ok := values[paramName]
+		if !ok {
+			return nil, fmt.Errorf("tigertonic: URL for route %q missing value for parameter %q", name, paramName)
+		}
+		if nil != constraint && !constraint.MatchString(value) {
+			return nil, fmt.Errorf("tigertonic: URL for route %q value %q does not satisfy the constraint on parameter %q", name, value, paramName)
+		}
+		segments[i] = value
+	}
+	return &url.URL{Path: strings.Join(segments, "/")}, nil
+}
@@ -0,0 +1,54 @@
+package tigertonic
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestURLSubstitutesParameters(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.HandleNamed("user", "GET", "/users/{id:int}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	u, err := mux.URL("user", "id", "42")
+	if nil != err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if "/users/42" != u.Path {
+		t.Fatalf("expected /users/42, got %q", u.Path)
+	}
+}
+
+func TestURLUnknownRouteName(t *testing.T) {
+	mux := NewTrieServeMux()
+
+	if _, err := mux.URL("does-not-exist"); nil == err {
+		t.Fatal("expected an error for an unknown route name")
+	}
+}
+
+func TestURLMissingParameter(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.HandleNamed("user", "GET", "/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	if _, err := mux.URL("user"); nil == err {
+		t.Fatal("expected an error when a pattern parameter has no value")
+	}
+}
+
+func TestURLOddPairs(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.HandleNamed("user", "GET", "/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	if _, err := mux.URL("user", "id"); nil == err {
+		t.Fatal("expected an error for an odd number of key/value pairs")
+	}
+}
+
+func TestURLValueFailsConstraint(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.HandleNamed("user", "GET", "/users/{id:int}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	if _, err := mux.URL("user", "id", "not-an-int"); nil == err {
+		t.Fatal("expected an error when a value does not satisfy its constraint")
+	}
+}
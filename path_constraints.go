@@ -0,0 +1,51 @@
+package tigertonic
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pathConstraints holds the named regular expressions that may be referenced
+// from a URL pattern as "{name:constraint}".  It is pre-populated with a
+// small set of constraints useful for typical REST routing and may be
+// extended with RegisterPathConstraint.
+var pathConstraints = map[string]*regexp.Regexp{
+	"int":   regexp.MustCompile(`^[0-9]+$`),
+	"uuid":  regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+	"alpha": regexp.MustCompile(`^[a-zA-Z]+$`),
+	"alnum": regexp.MustCompile(`^[a-zA-Z0-9]+$`),
+}
+
+// RegisterPathConstraint registers a named regular expression that can
+// afterwards be referenced by name as the constraint of a URL pattern
+// segment "{param:name}", for example "{id:int}" or "{code:alnum}".  It is intended to be called from
+// init functions, before any TrieServeMuxes are built from patterns that
+// reference the constraint.
+func RegisterPathConstraint(name string, re *regexp.Regexp) {
+	pathConstraints[name] = re
+}
+
+// parseConstraint splits a brace-delimited path segment such as "{foo}" or
+// "{foo:constraint}" into the bare parameter name and, when a constraint is
+// present, the compiled regular expression that constrains it.  constraint
+// may be the name of a constraint registered with RegisterPathConstraint or
+// an inline regular expression.  It panics if constraint is neither, since
+// that indicates a programmer error in the registered pattern.
+func parseConstraint(seg string) (string, *regexp.Regexp) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}")
+	name, constraint := inner, ""
+	if i := strings.Index(inner, ":"); i >= 0 {
+		name, constraint = inner[:i], inner[i+1:]
+	}
+	if "" == constraint {
+		return name, nil
+	}
+	if re, ok := pathConstraints[constraint]; ok {
+		return name, re
+	}
+	re, err := regexp.Compile("^" + constraint + "$")
+	if nil != err {
+		panic("tigertonic: invalid path constraint \"" + constraint + "\": " + err.Error())
+	}
+	return name, re
+}
@@ -0,0 +1,65 @@
+package tigertonic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConstrainedParamsCoexistAtSamePosition(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/users/{id:int}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "id")
+	}))
+	mux.Handle("GET", "/users/{slug:alpha}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "slug")
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/users/42", nil))
+	if http.StatusOK != w.Code {
+		t.Fatalf("GET /users/42: expected 200, got %d", w.Code)
+	}
+	if "id" != w.Header().Get("X-Matched") {
+		t.Fatalf("GET /users/42: expected the int-constrained route, got %q", w.Header().Get("X-Matched"))
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/users/jane", nil))
+	if http.StatusOK != w.Code {
+		t.Fatalf("GET /users/jane: expected 200, got %d", w.Code)
+	}
+	if "slug" != w.Header().Get("X-Matched") {
+		t.Fatalf("GET /users/jane: expected the alpha-constrained route, got %q", w.Header().Get("X-Matched"))
+	}
+}
+
+func TestConstrainedParamFallsThroughToUnconstrained(t *testing.T) {
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/users/{id:int}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "id")
+	}))
+	mux.Handle("GET", "/users/{name}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Matched", "name")
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/users/not-an-int!", nil))
+	if http.StatusOK != w.Code {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if "name" != w.Header().Get("X-Matched") {
+		t.Fatalf("expected the unconstrained fallback route, got %q", w.Header().Get("X-Matched"))
+	}
+}
+
+func TestSecondUnconstrainedParamAtSamePositionPanics(t *testing.T) {
+	defer func() {
+		if nil == recover() {
+			t.Fatal("expected registering a second unconstrained param at the same position to panic")
+		}
+	}()
+	mux := NewTrieServeMux()
+	mux.Handle("GET", "/users/{name}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux.Handle("POST", "/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 )
@@ -17,19 +18,51 @@ import (
 // Components of the URL pattern surrounded by braces (for example: "{foo}")
 // match any string and create an entry for the string plus the string
 // surrounded by braces in the query parameters (for example: "foo" and
-// "{foo}").
+// "{foo}").  A brace component may also carry a constraint, written as
+// "{foo:constraint}", where constraint is either the name of a constraint
+// registered with RegisterPathConstraint (see path_constraints.go) or an
+// inline regular expression.  A constrained component only matches segments
+// accepted by its regular expression, which allows patterns like
+// "/users/{id:int}" and "/users/me" to coexist as distinct routes.
 type TrieServeMux struct {
-	methods map[string]http.Handler
-	param   *string
-	paths   map[string]*TrieServeMux
-	pattern string
+	// AutoHead, when true (the default), dispatches a HEAD request to a
+	// route's GET handler with the response body discarded, when no HEAD
+	// handler has been registered explicitly for that route.  Set it to
+	// false to require HEAD handlers to be registered explicitly instead.
+	//
+	// AutoHead is only consulted on the TrieServeMux that Handler or
+	// ServeHTTP is called on; the copy on a descendant node created
+	// internally by add is never read.
+	AutoHead bool
+
+	corsPolicy       *CORSPolicy
+	methods          map[string]http.Handler
+	methodMiddleware map[string][]Middleware
+	middleware       []Middleware
+	names            map[string]string
+	params           []*paramChild
+	paths            map[string]*TrieServeMux
+	pattern          string
+}
+
+// paramChild records one "{name}" or "{name:constraint}" path segment
+// registered at a trie node, so that several differently-constrained
+// variants (for example "{id:int}" and "{slug:alpha}") can coexist at the
+// same position and be tried against the incoming segment in turn.
+type paramChild struct {
+	raw        string
+	name       string
+	constraint *regexp.Regexp
 }
 
 // NewTrieServeMux makes a new TrieServeMux.
 func NewTrieServeMux() *TrieServeMux {
 	return &TrieServeMux{
-		methods: make(map[string]http.Handler),
-		paths:   make(map[string]*TrieServeMux),
+		AutoHead:         true,
+		methods:          make(map[string]http.Handler),
+		methodMiddleware: make(map[string][]Middleware),
+		names:            make(map[string]string),
+		paths:            make(map[string]*TrieServeMux),
 	}
 }
 
@@ -60,16 +93,31 @@ func (mux *TrieServeMux) HandleNamespace(namespace string, handler http.Handler)
 // a relatively standard interface and is most used in testing where behavior
 // like this can be allowed.
 func (mux *TrieServeMux) Handler(r *http.Request) (http.Handler, string) {
-	params, handler, pattern := mux.find(r, strings.Split(r.URL.Path, "/")[1:])
-	r.URL.RawQuery = r.URL.RawQuery + "&" + params.Encode()
+	handler, pattern, _ := mux.resolve(r)
 	return handler, pattern
 }
 
+// resolve is the shared implementation behind Handler and ServeHTTP.  It
+// mutates the querystring to add wildcards extracted from the URL, wraps the
+// matched handler in its registered middlewares, and additionally returns
+// the most specific CORSPolicy along the matched path, if any.
+func (mux *TrieServeMux) resolve(r *http.Request) (http.Handler, string, *CORSPolicy) {
+	params, handler, pattern, mw, policy := mux.find(r, strings.Split(r.URL.Path, "/")[1:], mux.AutoHead)
+	r.URL.RawQuery = r.URL.RawQuery + "&" + params.Encode()
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler, pattern, policy
+}
+
 // ServeHTTP routes an HTTP request to the http.Handler registered for the URL
 // pattern which matches the requested path.  It responds 404 if there is no
 // matching URL pattern and 405 if the requested HTTP method is not allowed.
 func (mux *TrieServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	handler, _ := mux.Handler(r)
+	handler, _, policy := mux.resolve(r)
+	if nil != policy {
+		handler = policy.wrap(handler)
+	}
 	handler.ServeHTTP(w, r)
 }
 
@@ -83,7 +131,7 @@ func (mux *TrieServeMux) add(method string, paths []string, handler http.Handler
 		return
 	}
 	if strings.HasPrefix(paths[0], "{") && strings.HasSuffix(paths[0], "}") {
-		mux.param = &paths[0]
+		mux.addParam(paths[0], pattern)
 	}
 	if _, ok := mux.paths[paths[0]]; !ok {
 		mux.paths[paths[0]] = NewTrieServeMux()
@@ -91,33 +139,87 @@ func (mux *TrieServeMux) add(method string, paths []string, handler http.Handler
 	mux.paths[paths[0]].add(method, paths[1:], handler, pattern)
 }
 
+// addParam registers raw, a "{name}" or "{name:constraint}" path segment, as
+// a param slot on mux, unless it is already registered.  Any number of
+// distinctly constrained params may share a position; find tries them, in
+// registration order, against the incoming segment, and tries the
+// unconstrained param, if any, only when none of them matches.  A second
+// unconstrained param at a position that already has one is ambiguous and
+// panics, since there would be no way to choose between them.
+func (mux *TrieServeMux) addParam(raw, pattern string) {
+	for _, p := range mux.params {
+		if p.raw == raw {
+			return
+		}
+	}
+	name, constraint := parseConstraint(raw)
+	if nil == constraint {
+		for _, p := range mux.params {
+			if nil == p.constraint {
+				panic(fmt.Sprintf("tigertonic: pattern %q registers unconstrained path parameter %q alongside existing unconstrained %q at the same position", pattern, raw, p.raw))
+			}
+		}
+	}
+	mux.params = append(mux.params, &paramChild{raw: raw, name: name, constraint: constraint})
+}
+
 // find recursively searches for a URL pattern in the trie, strips
 // namespace components from the URL, adds wildcards to the query parameters,
-// and returns extra query parameters, a handler, and the pattern that matched.
-func (mux *TrieServeMux) find(r *http.Request, paths []string) (url.Values, http.Handler, string) {
+// and returns extra query parameters, a handler, the pattern that matched,
+// the middlewares registered along the way from the root to the matched
+// node (outermost first), and the most specific CORSPolicy registered along
+// the way, if any.  autoHead is the root mux's AutoHead setting, threaded
+// through the recursion since only the root's value is ever consulted.
+func (mux *TrieServeMux) find(r *http.Request, paths []string, autoHead bool) (url.Values, http.Handler, string, []Middleware, *CORSPolicy) {
 	if 0 == len(paths) {
 		if handler, ok := mux.methods[r.Method]; ok {
-			return nil, handler, mux.pattern
+			return nil, handler, mux.pattern, concatMiddleware(mux.middleware, mux.methodMiddleware[r.Method]), mux.corsPolicy
+		}
+		if "HEAD" == r.Method && autoHead {
+			if get, ok := mux.methods["GET"]; ok {
+				return nil, headHandler{get}, mux.pattern, concatMiddleware(mux.middleware, mux.methodMiddleware["GET"]), mux.corsPolicy
+			}
 		}
-		return nil, methodNotAllowedHandler{mux}, ""
+		return nil, methodNotAllowedHandler{mux}, "", mux.middleware, mux.corsPolicy
 	}
 	if _, ok := mux.paths[paths[0]]; ok {
-		return mux.paths[paths[0]].find(r, paths[1:])
+		params, handler, pattern, mw, policy := mux.paths[paths[0]].find(r, paths[1:], autoHead)
+		if nil == policy {
+			policy = mux.corsPolicy
+		}
+		return params, handler, pattern, concatMiddleware(mux.middleware, mw), policy
 	}
-	if nil != mux.param {
-		params, handler, pattern := mux.paths[*mux.param].find(r, paths[1:])
-		if nil == params {
-			params = make(url.Values)
+	for _, p := range mux.params {
+		if nil != p.constraint && p.constraint.MatchString(paths[0]) {
+			return mux.descendParam(p, r, paths, autoHead)
+		}
+	}
+	for _, p := range mux.params {
+		if nil == p.constraint {
+			return mux.descendParam(p, r, paths, autoHead)
 		}
-		params.Set(*mux.param, paths[0])
-		params.Set(strings.Trim(*mux.param, "{}"), paths[0])
-		return params, handler, pattern
 	}
 	if handler, ok := mux.methods[""]; ok {
 		r.URL.Path = "/" + strings.Join(paths, "/")
-		return nil, handler, mux.pattern
+		return nil, handler, mux.pattern, mux.middleware, mux.corsPolicy
+	}
+	return nil, NotFoundHandler(), "", mux.middleware, mux.corsPolicy
+}
+
+// descendParam continues find through the child node registered for p,
+// recording paths[0] as the value of both p's raw brace segment and its bare
+// name in the returned query parameters.
+func (mux *TrieServeMux) descendParam(p *paramChild, r *http.Request, paths []string, autoHead bool) (url.Values, http.Handler, string, []Middleware, *CORSPolicy) {
+	params, handler, pattern, mw, policy := mux.paths[p.raw].find(r, paths[1:], autoHead)
+	if nil == params {
+		params = make(url.Values)
 	}
-	return nil, NotFoundHandler(), ""
+	params.Set(p.raw, paths[0])
+	params.Set(p.name, paths[0])
+	if nil == policy {
+		policy = mux.corsPolicy
+	}
+	return params, handler, pattern, concatMiddleware(mux.middleware, mw), policy
 }
 
 type methodNotAllowedHandler struct {
@@ -135,28 +237,6 @@ func (h methodNotAllowedHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	sort.Strings(methods)
 	w.Header().Set("Allow", strings.Join(methods, ", "))
 	if "OPTIONS" == r.Method {
-		if method := r.Header.Get(CORSRequestMethod); method != "" {
-			w.Header().Set(CORSAllowMethods, strings.Join(methods, ", "))
-			if requestOrigin := r.Header.Get(CORSRequestOrigin); requestOrigin != "" {
-				allowedOrigin := ""
-				if cors, ok := h.mux.methods[method].(*CORSHandler); ok {
-					allowedOrigin = cors.getAllowedOrigin(requestOrigin)
-				}
-
-				if allowedOrigin == "" {
-					allowedOrigin = "null"
-				}
-				w.Header().Set(CORSAllowOrigin, allowedOrigin)
-			}
-			if requestHeaders := r.Header.Get(CORSRequestHeaders); requestHeaders != "" {
-				allowedHeaders := ""
-				if cors, ok := h.mux.methods[method].(*CORSHandler); ok {
-					allowedHeaders = cors.getAllowedHeaders()
-				}
-				w.Header().Set(CORSAllowHeaders, allowedHeaders)
-			}
-
-		}
 		if acceptJSON(r) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)